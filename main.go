@@ -2,17 +2,24 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"syscall"
 	"time"
 
-	// "log"
-
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -38,20 +45,53 @@ type Environment struct {
 	dbCollection string
 	dbUsername   string
 	dbPassword   string
+	dbTLSCA      string
+	dbTLSCert    string
+	dbTLSKey     string
 	serverHost   string
 	serverPort   string
+	logLevel     string
+
+	dbTimeout               time.Duration
+	serverReadHeaderTimeout time.Duration
+	serverWriteTimeout      time.Duration
+	serverIdleTimeout       time.Duration
+	serverShutdownTimeout   time.Duration
 }
 
 var env Environment = Environment{
 	dbURI:        getEnv("DB_URI", "mongodb://127.0.0.1:27017"),
 	dbName:       getEnv("DB_NAME", "simple_crud"),
 	dbCollection: getEnv("DB_COLLECTION", "simple_crud"),
-	dbUsername:   getEnv("DB_USERNAME", "admin"),
-	dbPassword:   getEnv("DB_PASSWORD", "admin"),
+	dbUsername:   getEnv("DB_USERNAME", ""),
+	dbPassword:   getEnv("DB_PASSWORD", ""),
+	dbTLSCA:      getEnv("DB_TLS_CA", ""),
+	dbTLSCert:    getEnv("DB_TLS_CERT", ""),
+	dbTLSKey:     getEnv("DB_TLS_KEY", ""),
 	serverHost:   getEnv("SERVER_HOST", "0.0.0.0"),
 	serverPort:   getEnv("SERVER_PORT", "8080"),
+	logLevel:     getEnv("LOG_LEVEL", "info"),
+
+	dbTimeout:               getEnvDuration("DB_TIMEOUT", time.Second*2),
+	serverReadHeaderTimeout: getEnvDuration("SERVER_READ_HEADER_TIMEOUT", time.Second*5),
+	serverWriteTimeout:      getEnvDuration("SERVER_WRITE_TIMEOUT", 0),
+	serverIdleTimeout:       getEnvDuration("SERVER_IDLE_TIMEOUT", time.Second*120),
+	serverShutdownTimeout:   getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", time.Second*10),
+}
+
+// buildLogger configures the process-wide structured logger from LOG_LEVEL
+// (debug/info/warn/error). An unrecognized level falls back to info.
+func buildLogger() *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(env.logLevel)); err != nil {
+		level = slog.LevelInfo
+	}
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+	return slog.New(handler)
 }
 
+var logger = buildLogger()
+
 var client *mongo.Client
 var mainContext context.Context
 var collection *mongo.Collection
@@ -63,6 +103,24 @@ func makeResponse(err string, code int) (map[string]any, int) {
 	return res, code
 }
 
+// translateError maps a DB-layer error to the response body/status a client
+// should see. Only a mongo.CommandError passes the server's error message
+// through (with a 502); any other unrecognized error becomes a generic 500.
+func translateError(err error) (map[string]any, int) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return makeResponse("No item found", http.StatusNotFound)
+	case errors.Is(err, context.DeadlineExceeded):
+		return makeResponse("Database operation timed out", http.StatusGatewayTimeout)
+	default:
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) {
+			return makeResponse(cmdErr.Message, http.StatusBadGateway)
+		}
+		return makeResponse("Internal server error", http.StatusInternalServerError)
+	}
+}
+
 func jsonToMap(jsonString []byte) (map[string]any, error) {
 	result := make(map[string]any)
 	if err := json.Unmarshal(jsonString, &result); err != nil {
@@ -74,7 +132,7 @@ func jsonToMap(jsonString []byte) (map[string]any, error) {
 func readBody(req *http.Request) (map[string]any, error) {
 	body, err := io.ReadAll(req.Body)
 	if err != nil {
-		log.Panic(err.Error())
+		return nil, err
 	}
 	if len(body) != 0 {
 		return jsonToMap(body)
@@ -89,121 +147,406 @@ func getEnv(envVar string, fallback string) string {
 	return fallback
 }
 
-func getSR(path string) *mongo.SingleResult {
-	ctx, cancel := context.WithTimeout(mainContext, time.Second*2)
+// getEnvDuration parses a Go duration string (e.g. "2s") from envVar,
+// falling back when the variable is unset or unparsable. SERVER_WRITE_TIMEOUT
+// defaults to 0 (disabled) so the /subscribe SSE stream isn't cut off.
+func getEnvDuration(envVar string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(envVar)
+	if raw == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return fallback
+	}
+	return d
+}
+
+// buildTLSConfig loads an optional CA bundle and client certificate for the
+// Mongo connection from DB_TLS_CA / DB_TLS_CERT / DB_TLS_KEY. It returns a
+// nil config (and no error) when none of those are set, so the connection
+// falls back to whatever `DB_URI` already specifies.
+func buildTLSConfig() (*tls.Config, error) {
+	if env.dbTLSCA == "" && env.dbTLSCert == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+
+	if env.dbTLSCA != "" {
+		caBytes, err := os.ReadFile(env.dbTLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("reading DB_TLS_CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBytes) {
+			return nil, fmt.Errorf("no certificates parsed from DB_TLS_CA %s", env.dbTLSCA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if env.dbTLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(env.dbTLSCert, env.dbTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("loading DB_TLS_CERT/DB_TLS_KEY: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func getSR(ctx context.Context, path string) (*mongo.SingleResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, env.dbTimeout)
 	defer cancel()
 
 	sr := collection.FindOne(ctx, bson.D{{Key: "path", Value: path}})
-	if errors.Is(sr.Err(), mongo.ErrNoDocuments) {
-		return nil
-	} else if sr.Err() != nil {
-		log.Panic(sr.Err().Error())
+	if err := sr.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
 	}
-	return sr
+	return sr, nil
 }
 
-func getItem(path string) (map[string]any, int) {
+func getItem(ctx context.Context, path string) (map[string]any, error) {
+	sr, err := getSR(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if sr == nil {
+		return nil, nil
+	}
+
 	var i map[string]any
-	if data := getSR(path); data == nil {
-		i = make(map[string]any)
-		return makeResponse("No item found", http.StatusNotFound)
-	} else {
-		if err := data.Decode(&i); err != nil {
-			log.Panic(err.Error())
-		}
-		return i, http.StatusOK
+	if err := sr.Decode(&i); err != nil {
+		return nil, err
 	}
+	return i, nil
 }
 
-func createOrOverwriteItem(path string, data map[string]any) (map[string]any, int) {
-	ctx, cancel := context.WithTimeout(mainContext, time.Second*2)
+func createOrOverwriteItem(ctx context.Context, path string, data map[string]any) (*mongo.UpdateResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, env.dbTimeout)
 	defer cancel()
 
 	data["path"] = path
 
-	if getSR(path) == nil {
-		if _, err := collection.InsertOne(ctx, data); err != nil {
-			log.Panic(err.Error())
-		}
-	} else {
-		if _, err := collection.ReplaceOne(ctx, bson.D{{Key: "path", Value: path}}, data); err != nil {
-			log.Panic(err.Error())
-		}
+	return collection.ReplaceOne(ctx, bson.D{{Key: "path", Value: path}}, data, options.Replace().SetUpsert(true))
+}
+
+func createOrUpdateItem(ctx context.Context, path string, data map[string]any) (*mongo.UpdateResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, env.dbTimeout)
+	defer cancel()
+
+	data["path"] = path
+
+	return collection.UpdateOne(ctx, bson.D{{Key: "path", Value: path}}, bson.M{"$set": data}, options.Update().SetUpsert(true))
+}
+
+func listItems(ctx context.Context, prefix string, limit int64, skip int64, sort string) ([]map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, env.dbTimeout)
+	defer cancel()
+
+	filter := bson.D{}
+	if prefix != "" {
+		filter = bson.D{{Key: "path", Value: bson.D{{Key: "$regex", Value: "^" + regexp.QuoteMeta(prefix)}}}}
 	}
 
-	return makeResponse("Ok", http.StatusOK)
+	findOpts := options.Find()
+	if limit > 0 {
+		findOpts.SetLimit(limit)
+	}
+	if skip > 0 {
+		findOpts.SetSkip(skip)
+	}
+	if sort != "" {
+		findOpts.SetSort(bson.D{{Key: sort, Value: 1}})
+	}
+
+	cursor, err := collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	items := make([]map[string]any, 0)
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
 }
 
-func createOrUpdateItem(path string, data map[string]any) (map[string]any, int) {
-	ctx, cancel := context.WithTimeout(mainContext, time.Second*2)
+func deleteItem(ctx context.Context, path string) (*mongo.DeleteResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, env.dbTimeout)
 	defer cancel()
 
-	data["path"] = path
+	return collection.DeleteOne(ctx, bson.D{{Key: "path", Value: path}})
+}
+
+func writeJSON(rw http.ResponseWriter, body any, code int) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		logger.Error("marshaling response", "error", err)
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(`{"message":"Internal server error","status":500}`))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	rw.Write(data)
+}
+
+// recoverMiddleware catches panics from the wrapped handler so a single bad
+// request can't crash the goroutine and leave the client with a broken
+// connection. It logs the panic and stack at error level and responds with
+// a normal 500 JSON body instead.
+func recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("panic handling request",
+					"method", req.Method,
+					"path", req.URL.Path,
+					"panic", r,
+					"stack", string(debug.Stack()),
+				)
+				body, code := makeResponse("Internal server error", http.StatusInternalServerError)
+				writeJSON(rw, body, code)
+			}
+		}()
+		next.ServeHTTP(rw, req)
+	})
+}
 
-	if getSR(path) == nil {
-		if _, err := collection.InsertOne(ctx, data); err != nil {
-			log.Panic(err.Error())
+func itemHandler(rw http.ResponseWriter, req *http.Request) {
+	path := req.PathValue("path")
+	reqBody, err := readBody(req)
+	if err != nil {
+		body, code := makeResponse("Error reading JSON body", http.StatusBadRequest)
+		writeJSON(rw, body, code)
+		return
+	}
+
+	logger.Debug("handling item request", "method", req.Method, "path", path)
+
+	ctx := req.Context()
+
+	var body map[string]any
+	var code int
+
+	switch req.Method {
+
+	case http.MethodGet:
+		item, err := getItem(ctx, path)
+		switch {
+		case err != nil:
+			body, code = translateError(err)
+		case item == nil:
+			body, code = makeResponse("No item found", http.StatusNotFound)
+		default:
+			body, code = item, http.StatusOK
 		}
-	} else {
-		if _, err := collection.UpdateOne(ctx, bson.D{{Key: "path", Value: path}}, data); err != nil {
-			return makeResponse(err.Error(), http.StatusBadRequest)
+
+	case http.MethodPost:
+		res, err := createOrUpdateItem(ctx, path, reqBody)
+		if err != nil {
+			body, code = translateError(err)
+		} else {
+			body, code = makeResponse("Ok", http.StatusOK)
+			body["upsertedId"] = res.UpsertedID
+			body["matchedCount"] = res.MatchedCount
+			body["modifiedCount"] = res.ModifiedCount
 		}
+
+	case http.MethodPut:
+		res, err := createOrOverwriteItem(ctx, path, reqBody)
+		if err != nil {
+			body, code = translateError(err)
+		} else {
+			body, code = makeResponse("Ok", http.StatusOK)
+			body["upsertedId"] = res.UpsertedID
+			body["matchedCount"] = res.MatchedCount
+			body["modifiedCount"] = res.ModifiedCount
+		}
+
+	case http.MethodDelete:
+		dr, err := deleteItem(ctx, path)
+		if err != nil {
+			body, code = translateError(err)
+		} else {
+			body, code = makeResponse(fmt.Sprintf("Deleted count: %d", dr.DeletedCount), http.StatusOK)
+		}
+
+	default:
+		body, code = makeResponse("Unsupported method", http.StatusBadRequest)
 	}
 
-	return makeResponse("Ok", http.StatusOK)
+	writeJSON(rw, body, code)
 }
 
-func deleteItem(path string) (map[string]any, int) {
-	ctx, cancel := context.WithTimeout(mainContext, time.Second*2)
-	defer cancel()
+// parseQueryInt reads a decimal query parameter, returning fallback when it
+// is absent.
+func parseQueryInt(query url.Values, key string, fallback int64) (int64, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return fallback, nil
+	}
+	return strconv.ParseInt(raw, 10, 64)
+}
 
-	dr, err := collection.DeleteOne(ctx, bson.D{{Key: "path", Value: path}})
+func itemsHandler(rw http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	limit, err := parseQueryInt(query, "limit", 0)
 	if err != nil {
-		log.Panic(err.Error())
+		body, code := makeResponse("Invalid limit", http.StatusBadRequest)
+		writeJSON(rw, body, code)
+		return
 	}
 
-	return makeResponse(fmt.Sprintf("Deleted count: %d", dr.DeletedCount), http.StatusOK)
+	skip, err := parseQueryInt(query, "skip", 0)
+	if err != nil {
+		body, code := makeResponse("Invalid skip", http.StatusBadRequest)
+		writeJSON(rw, body, code)
+		return
+	}
+
+	items, err := listItems(req.Context(), query.Get("prefix"), limit, skip, query.Get("sort"))
+	if err != nil {
+		body, code := translateError(err)
+		writeJSON(rw, body, code)
+		return
+	}
+
+	writeJSON(rw, items, http.StatusOK)
 }
 
-func mainHandler(rw http.ResponseWriter, req *http.Request) {
-	response := newResponse()
+// subscribeHandler tails inserts/updates/replaces/deletes under `prefix` via
+// a Mongo change stream and pushes each one to the client as a
+// Server-Sent Event. A `Last-Event-ID` header carrying a previously seen
+// resume token lets the client pick back up after a dropped connection.
+func subscribeHandler(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		body, code := makeResponse("Unsupported method", http.StatusBadRequest)
+		writeJSON(rw, body, code)
+		return
+	}
 
-	path := req.RequestURI
-	body, err := readBody(req)
-	log.Printf("Action %s to %s with: %s, err: %v", req.Method, path, body, err)
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		body, code := makeResponse("Streaming unsupported", http.StatusInternalServerError)
+		writeJSON(rw, body, code)
+		return
+	}
 
-	if err != nil {
-		response.body, response.code = makeResponse("Error reading JSON body", http.StatusBadRequest)
-	} else {
-
-		switch req.Method {
-
-		case http.MethodGet:
-			response.body, response.code = getItem(path)
-		case http.MethodPost:
-			response.body, response.code = createOrUpdateItem(path, body)
-		case http.MethodPut:
-			response.body, response.code = createOrOverwriteItem(path, body)
-		case http.MethodDelete:
-			response.body, response.code = deleteItem(path)
-		default:
-			response.body, response.code = makeResponse("Unsupported method", http.StatusBadRequest)
+	pipeline := mongo.Pipeline{}
+	if prefix := req.URL.Query().Get("prefix"); prefix != "" {
+		// Deletes carry no `fullDocument`, so matching on it alone would
+		// silently drop every delete under the prefix. Also match on
+		// `fullDocumentBeforeChange.path`, populated when the collection has
+		// pre-images enabled.
+		regex := bson.D{{Key: "$regex", Value: "^" + regexp.QuoteMeta(prefix)}}
+		pipeline = mongo.Pipeline{
+			{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "fullDocument.path", Value: regex}},
+				bson.D{{Key: "fullDocumentBeforeChange.path", Value: regex}},
+			}}}}},
 		}
 	}
 
-	rw.Header().Set("Content-Type", "application/json")
-	rw.WriteHeader(response.code)
-	json_bytes, err := json.Marshal(response.body)
+	streamOpts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+	if resumeToken := req.Header.Get("Last-Event-ID"); resumeToken != "" {
+		var token bson.Raw
+		if err := bson.UnmarshalExtJSON([]byte(resumeToken), false, &token); err != nil {
+			body, code := makeResponse("Invalid Last-Event-ID", http.StatusBadRequest)
+			writeJSON(rw, body, code)
+			return
+		}
+		streamOpts.SetResumeAfter(token)
+	}
+
+	ctx := req.Context()
+	stream, err := collection.Watch(ctx, pipeline, streamOpts)
 	if err != nil {
-		log.Panic(err.Error())
+		body, code := translateError(err)
+		writeJSON(rw, body, code)
+		return
+	}
+	defer stream.Close(ctx)
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for stream.Next(ctx) {
+		var event struct {
+			OperationType            string         `bson:"operationType"`
+			FullDocument             map[string]any `bson:"fullDocument"`
+			FullDocumentBeforeChange map[string]any `bson:"fullDocumentBeforeChange"`
+		}
+		if err := stream.Decode(&event); err != nil {
+			logger.Warn("decoding change event", "error", err)
+			continue
+		}
+
+		path, _ := event.FullDocument["path"].(string)
+		if path == "" {
+			path, _ = event.FullDocumentBeforeChange["path"].(string)
+		}
+
+		// Extended JSON, not the raw bson.Raw bytes, so a client can echo it
+		// straight back as Last-Event-ID for bson.UnmarshalExtJSON above.
+		resumeToken := stream.ResumeToken().String()
+		payload := map[string]any{
+			"op":          event.OperationType,
+			"path":        path,
+			"document":    event.FullDocument,
+			"resumeToken": resumeToken,
+		}
+
+		data, err := json.Marshal(payload)
+		if err != nil {
+			logger.Warn("marshaling change event", "error", err)
+			continue
+		}
+
+		fmt.Fprintf(rw, "id: %s\ndata: %s\n\n", resumeToken, data)
+		flusher.Flush()
+	}
+
+	if err := stream.Err(); err != nil {
+		logger.Error("change stream error", "error", err)
 	}
-	rw.Write(json_bytes)
 }
 
 func init() {
 	// Init DB connection
-	var err error
-	client, err = mongo.Connect(options.Client().ApplyURI(env.dbURI))
+	clientOpts := options.Client().ApplyURI(env.dbURI)
+
+	if env.dbUsername != "" && env.dbPassword != "" {
+		clientOpts.SetAuth(options.Credential{
+			Username: env.dbUsername,
+			Password: env.dbPassword,
+		})
+	}
+
+	tlsConfig, err := buildTLSConfig()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	if tlsConfig != nil {
+		clientOpts.SetTLSConfig(tlsConfig)
+	}
+
+	client, err = mongo.Connect(clientOpts)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -213,26 +556,60 @@ func init() {
 }
 
 func main() {
-	ctx, cancel := context.WithTimeout(mainContext, time.Second*5)
-	defer cancel()
-	defer func() {
-		if err := client.Disconnect(ctx); err != nil {
-			log.Panic(err.Error())
-		}
-	}()
+	bootCtx, bootCancel := context.WithTimeout(mainContext, time.Second*5)
 
-	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+	if err := client.Ping(bootCtx, readpref.Primary()); err != nil {
 		log.Panic(err.Error())
 	}
 
 	collection = client.Database(env.dbName).Collection(env.dbCollection)
 
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "path", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(bootCtx, indexModel); err != nil {
+		log.Panic(err.Error())
+	}
+	bootCancel()
+
 	log.Printf("Runnging go server on %s:%s \n", env.serverHost, env.serverPort)
 	log.Printf("Mongodb on %s use %s collection %s\n", env.dbURI, env.dbName, env.dbCollection)
 
-	http.HandleFunc("/", mainHandler)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items", itemsHandler)
+	mux.HandleFunc("/items/{path...}", itemHandler)
+	mux.HandleFunc("GET /subscribe", subscribeHandler)
+
+	srv := &http.Server{
+		Addr:              env.serverHost + ":" + env.serverPort,
+		Handler:           recoverMiddleware(mux),
+		ReadHeaderTimeout: env.serverReadHeaderTimeout,
+		WriteTimeout:      env.serverWriteTimeout,
+		IdleTimeout:       env.serverIdleTimeout,
+	}
+
+	notifyCtx, stop := signal.NotifyContext(mainContext, os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	if err := http.ListenAndServe(env.serverHost+":"+env.serverPort, nil); err != nil {
-		log.Fatal(err.Error())
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err.Error())
+		}
+	}()
+
+	<-notifyCtx.Done()
+	stop()
+	log.Println("Shutting down")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), env.serverShutdownTimeout)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Panic(err.Error())
+	}
+
+	if err := client.Disconnect(shutdownCtx); err != nil {
+		log.Panic(err.Error())
 	}
 }